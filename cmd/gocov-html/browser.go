@@ -0,0 +1,22 @@
+package main
+
+import (
+	"os/exec"
+	"runtime"
+)
+
+// openBrowser opens path in the user's default browser, mirroring
+// what `go tool cover -html` does via the unexported stdlib
+// cmd/internal/browser package.
+func openBrowser(path string) error {
+	var args []string
+	switch runtime.GOOS {
+	case "darwin":
+		args = []string{"open", path}
+	case "windows":
+		args = []string{"rundll32", "url.dll,FileProtocolHandler", path}
+	default:
+		args = []string{"xdg-open", path}
+	}
+	return exec.Command(args[0], args[1:]...).Start()
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
@@ -8,6 +10,7 @@ import (
 	"os"
 	"runtime"
 
+	"github.com/axw/gocov"
 	"github.com/matm/gocov-html/pkg/config"
 	"github.com/matm/gocov-html/pkg/themes"
 )
@@ -15,6 +18,8 @@ import (
 func main() {
 	log.SetFlags(0)
 
+	format := flag.String("format", "html", "output format: html, cobertura or json")
+	funcMode := flag.Bool("func", false, "print a per-function coverage summary instead of a report, like 'go tool cover -func'")
 	css := flag.String("s", "", "path to custom CSS file")
 	showVersion := flag.Bool("v", false, "show program version")
 	showDefaultCSS := flag.Bool("d", false, "output CSS of default theme")
@@ -26,6 +31,10 @@ func main() {
 	minFunctionCoverage := flag.Uint64("fmin", 0, "only show functions whose coverage is smaller than fmin")
 	maxPackageCoverage := flag.Uint64("pmax", 100, "only show packages whose coverage is greater than pmax")
 	minPackageCoverage := flag.Uint64("pmin", 0, "only show packages whose coverage is smaller than pmin")
+	mergeMode := flag.String("mode", string(themes.MergeModeCount), "merge mode when given multiple inputs: set or count")
+	outFile := flag.String("o", "", "write the report to this file instead of stdout")
+	htmlOpen := flag.Bool("html", false, "open the report in the default browser (writes to a temp file if -o is not set)")
+	verbose := flag.Bool("verbose", false, "print per-package statement counts to stderr")
 
 	flag.Parse()
 
@@ -68,7 +77,13 @@ func main() {
 		return
 	}
 
+	mm := themes.MergeMode(*mergeMode)
+	if mm != themes.MergeModeSet && mm != themes.MergeModeCount {
+		log.Fatalf("Invalid merge mode: %q\n", *mergeMode)
+	}
+
 	var r io.Reader
+	var mergedMode string
 	switch flag.NArg() {
 	case 0:
 		r = os.Stdin
@@ -78,7 +93,14 @@ func main() {
 			log.Fatal(err)
 		}
 	default:
-		log.Fatalf("Usage: %s data.json\n", os.Args[0])
+		var err error
+		if r, err = mergeInputs(flag.Args(), mm); err != nil {
+			log.Fatal(err)
+		}
+		// Merging re-encodes the inputs as gocov JSON, which loses the
+		// native profile's "mode: ..." header, so carry it forward
+		// explicitly from the merge mode used.
+		mergedMode = string(mm)
 	}
 
 	var sortOrderOpt = themes.SortOrder(*sortOrder)
@@ -97,8 +119,89 @@ func main() {
 		CoverageFunctionMax: uint8(*maxFunctionCoverage),
 		CoveragePackageMin:  uint8(*minPackageCoverage),
 		CoveragePackageMax:  uint8(*maxPackageCoverage),
+		CoverageMode:        mergedMode,
+		Verbose:             *verbose,
 	}
-	if err := themes.HTMLReportCoverage(r, opts); err != nil {
+
+	w, outPath, err := reportWriter(*outFile, *htmlOpen)
+	if err != nil {
 		log.Fatal(err)
 	}
+
+	switch {
+	case *funcMode:
+		err = themes.FuncReportCoverage(r, w, opts)
+	case *format == "html":
+		err = themes.HTMLReportCoverage(r, w, opts)
+	case *format == "cobertura":
+		err = themes.CoberturaReportCoverage(r, w, opts)
+	case *format == "json":
+		err = themes.JSONReportCoverage(r, w, opts)
+	default:
+		log.Fatalf("Unknown format: %q\n", *format)
+	}
+	if f, ok := w.(*os.File); ok && f != os.Stdout {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *htmlOpen {
+		if err := openBrowser(outPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// reportWriter resolves where the report should be written: outFile
+// if set, a temp file when htmlOpen asks to auto-open with no -o, or
+// stdout otherwise. outPath is the path of the file written to, or
+// empty when writing to stdout; main only uses it when htmlOpen is set,
+// to open that file in a browser afterwards.
+func reportWriter(outFile string, htmlOpen bool) (w io.Writer, outPath string, err error) {
+	switch {
+	case outFile != "":
+		f, err := os.Create(outFile)
+		if err != nil {
+			return nil, "", err
+		}
+		return f, outFile, nil
+	case htmlOpen:
+		f, err := os.CreateTemp("", "gocov-html-*.html")
+		if err != nil {
+			return nil, "", err
+		}
+		return f, f.Name(), nil
+	default:
+		return os.Stdout, "", nil
+	}
+}
+
+// mergeInputs reads and parses each of paths (gocov JSON or a native
+// Go coverage profile), merges them with mode, and re-encodes the
+// result as gocov JSON so it can flow through the same reader-based
+// report functions as a single input.
+func mergeInputs(paths []string, mode themes.MergeMode) (io.Reader, error) {
+	all := make([][]*gocov.Package, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		packages, _, err := themes.ParseCoverageData(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		all = append(all, packages)
+	}
+
+	merged := themes.Merge(mode, all...)
+	data, err := json.Marshal(struct{ Packages []*gocov.Package }{merged})
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
 }
@@ -0,0 +1,101 @@
+package themes
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/axw/gocov"
+)
+
+func TestStatementLines(t *testing.T) {
+	const src = `package sample
+
+func Add(a, b int) int {
+	return a + b
+}
+`
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sample.go")
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two statements on line 4 ("return a + b"), split across two
+	// profile blocks as a multi-input merge would produce; their hits
+	// must be summed onto that single line.
+	fn := &gocov.Function{
+		File: path,
+		Statements: []*gocov.Statement{
+			{Start: 42, End: 54, Reached: 2},
+			{Start: 42, End: 48, Reached: 1},
+		},
+	}
+
+	lines, err := statementLines(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want 1", len(lines))
+	}
+	if lines[0].Number != 4 {
+		t.Errorf("line number = %d, want 4", lines[0].Number)
+	}
+	if lines[0].Hits != 3 {
+		t.Errorf("hits = %d, want 3 (2+1)", lines[0].Hits)
+	}
+}
+
+func TestCoberturaReportCoverageFilenameRelative(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sample.go")
+	src := "package sample\n\nfunc Add(a, b int) int {\n\treturn a + b\n}\n"
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	input, err := json.Marshal(struct{ Packages []*gocov.Package }{
+		Packages: []*gocov.Package{{
+			Name: "sample",
+			Functions: []*gocov.Function{{
+				Name: "Add",
+				File: path,
+				Statements: []*gocov.Statement{
+					{Start: 42, End: 54, Reached: 1},
+				},
+			}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	opts := ReportOptions{CoverageFunctionMax: 100, CoveragePackageMax: 100}
+	if err := CoberturaReportCoverage(bytes.NewReader(input), &out, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	root, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantRel, err := filepath.Rel(root, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if filepath.IsAbs(wantRel) {
+		t.Skip("temp dir has no relative path to the working directory on this system")
+	}
+	if !strings.Contains(out.String(), `filename="`+wantRel+`"`) {
+		t.Errorf("expected filename attribute %q (relative to <source>), got:\n%s", wantRel, out.String())
+	}
+	if strings.Contains(out.String(), `filename="`+path+`"`) {
+		t.Errorf("filename attribute is an absolute path, want relative:\n%s", out.String())
+	}
+}
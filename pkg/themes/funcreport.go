@@ -0,0 +1,62 @@
+package themes
+
+import (
+	"fmt"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"os"
+	"text/tabwriter"
+
+	"github.com/axw/gocov"
+	"github.com/rotisserie/eris"
+)
+
+// functionStartLine returns the source line a function declaration
+// starts on, for the "file:line" column of the func report.
+func functionStartLine(fn *gocov.Function) (int, error) {
+	info, err := os.Stat(fn.File)
+	if err != nil {
+		return 0, err
+	}
+	fset := token.NewFileSet()
+	file := fset.AddFile(fn.File, fset.Base(), int(info.Size()))
+	data, err := ioutil.ReadFile(fn.File)
+	if err != nil {
+		return 0, err
+	}
+	file.SetLinesForContent(data)
+	return file.Line(file.Pos(fn.Start)), nil
+}
+
+// FuncReportCoverage prints a tab-aligned per-function coverage
+// summary to w, equivalent to `go tool cover -func`: one
+// "file:line funcName coveragePercent" row per function plus a final
+// "total: (statements) NN.N%" line.
+func FuncReportCoverage(r io.Reader, w io.Writer, opts ReportOptions) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return eris.Wrap(err, "read coverage data")
+	}
+	rep, err := loadReport(data, opts)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 1, '\t', 0)
+	var totalReached, totalStatements int
+	for _, pkg := range rep.packages {
+		rp := buildReportPackage(pkg, rep)
+		for _, fn := range rp.Functions {
+			line, err := functionStartLine(fn.Function)
+			if err != nil {
+				return eris.Wrap(err, "func report")
+			}
+			fmt.Fprintf(tw, "%s:%d:\t%s\t%.1f%%\n", fn.File, line, fn.Name, fn.CoveragePercent())
+		}
+		totalReached += rp.ReachedStatements
+		totalStatements += rp.TotalStatements
+	}
+	fmt.Fprintf(tw, "total:\t(statements)\t%.1f%%\n", lineRate(totalReached, totalStatements)*100)
+	return eris.Wrap(tw.Flush(), "func report")
+}
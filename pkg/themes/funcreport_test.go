@@ -0,0 +1,78 @@
+package themes
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/axw/gocov"
+)
+
+func TestFunctionStartLine(t *testing.T) {
+	const src = `package sample
+
+func Add(a, b int) int {
+	return a + b
+}
+`
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sample.go")
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fn := &gocov.Function{File: path, Start: 16}
+	line, err := functionStartLine(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if line != 3 {
+		t.Errorf("line = %d, want 3", line)
+	}
+}
+
+func TestFuncReportCoverage(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sample.go")
+	src := "package sample\n\nfunc Add(a, b int) int {\n\treturn a + b\n}\n"
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	input, err := json.Marshal(struct{ Packages []*gocov.Package }{
+		Packages: []*gocov.Package{{
+			Name: "sample",
+			Functions: []*gocov.Function{{
+				Name:  "Add",
+				File:  path,
+				Start: 16,
+				Statements: []*gocov.Statement{
+					{Start: 42, End: 54, Reached: 1},
+				},
+			}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	opts := ReportOptions{CoverageFunctionMax: 100, CoveragePackageMax: 100}
+	if err := FuncReportCoverage(bytes.NewReader(input), &out, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	got := out.String()
+	if !strings.Contains(got, path+":3:") {
+		t.Errorf("expected a row for %s:3:, got:\n%s", path, got)
+	}
+	if !strings.Contains(got, "Add") || !strings.Contains(got, "100.0%") {
+		t.Errorf("expected Add at 100%% coverage, got:\n%s", got)
+	}
+	if !strings.Contains(got, "total:") || !strings.Contains(got, "100.0%") {
+		t.Errorf("expected a total line at 100%%, got:\n%s", got)
+	}
+}
@@ -0,0 +1,83 @@
+package themes
+
+import (
+	"encoding/base64"
+	"html/template"
+)
+
+func init() {
+	registerTheme(golangTheme{})
+}
+
+// golangTheme is the default, built-in theme: one page per report,
+// styled close to the Go project's own generated docs.
+type golangTheme struct{}
+
+func (golangTheme) Name() string { return "golang" }
+
+func (golangTheme) Description() string {
+	return "Default theme, styled close to the Go project's own docs"
+}
+
+func (golangTheme) Data() ThemeData {
+	return ThemeData{
+		Style:  template.CSS(base64.StdEncoding.EncodeToString([]byte(golangCSS))),
+		Script: template.JS(base64.StdEncoding.EncodeToString([]byte(golangJS))),
+	}
+}
+
+func (golangTheme) Template() *template.Template {
+	return template.Must(template.New("golang").Parse(golangHTML))
+}
+
+const golangCSS = `
+body { font-family: sans-serif; margin: 2em; }
+.pkg { margin-bottom: 2em; }
+.fn { margin: 1em 0; }
+.fn-sig { font-weight: bold; }
+pre.src { background: #f6f6f6; padding: 0.5em; overflow-x: auto; }
+.src .line { display: block; }
+.src .miss { background: #ffe0e0; }
+.src .cov0  { background: #eaffea; }
+.src .cov1  { background: #d7ffd7; }
+.src .cov2  { background: #c4ffc4; }
+.src .cov3  { background: #b1ffb1; }
+.src .cov4  { background: #9eff9e; }
+.src .cov5  { background: #8bff8b; }
+.src .cov6  { background: #78ff78; }
+.src .cov7  { background: #65ff65; }
+.src .cov8  { background: #52ff52; }
+.src .cov9  { background: #3fff3f; }
+.src .cov10 { background: #2cff2c; }
+`
+
+const golangJS = ``
+
+const golangHTML = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Coverage report</title>
+<style>{{.Style}}</style>
+</head>
+<body>
+<p>{{.Command}}</p>
+{{if .Overview}}
+<p>Total: {{.Overview.PercentageReached}}%</p>
+{{end}}
+{{range $pkg := .Packages}}
+<div class="pkg">
+<h2>{{$pkg.Pkg.Name}} ({{printf "%.1f" $pkg.PercentageReached}}%)</h2>
+{{range $fn := $pkg.Functions}}
+<div class="fn">
+<div class="fn-sig">{{$fn.Name}} ({{printf "%.1f" $fn.CoveragePercent}}%) &mdash; {{$fn.ShortFileName}}</div>
+<pre class="src">{{range $line := $fn.Lines}}<span class="line{{if $line.Missed}} miss{{end}} cov{{$line.HeatBucket}}">{{printf "%4d" $line.LineNumber}}  {{$line.Code}}</span>
+{{end}}</pre>
+</div>
+{{end}}
+</div>
+{{end}}
+<script>{{.Script}}</script>
+</body>
+</html>
+`
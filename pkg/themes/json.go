@@ -0,0 +1,31 @@
+package themes
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/axw/gocov"
+	"github.com/rotisserie/eris"
+)
+
+// JSONReportCoverage writes the parsed coverage data back out as
+// gocov JSON to w. This is mainly useful to normalize a native Go
+// coverage profile into the format other gocov-based tools expect,
+// without going through the HTML or Cobertura rendering.
+func JSONReportCoverage(r io.Reader, w io.Writer, opts ReportOptions) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return eris.Wrap(err, "read coverage data")
+	}
+	packages, _, err := parseCoverageData(data)
+	if err != nil {
+		return eris.Wrap(err, "unmarshal coverage data")
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	err = enc.Encode(struct {
+		Packages []*gocov.Package
+	}{Packages: packages})
+	return eris.Wrap(err, "JSON report")
+}
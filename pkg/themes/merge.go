@@ -0,0 +1,79 @@
+package themes
+
+import "github.com/axw/gocov"
+
+// MergeMode controls how Merge combines a statement's Reached count
+// when it appears in more than one input, matching the two coverage
+// modes `go test -coverprofile` can produce.
+type MergeMode string
+
+const (
+	// MergeModeSet clamps a statement's merged Reached count to 0 or
+	// 1: it was hit, or it wasn't.
+	MergeModeSet MergeMode = "set"
+	// MergeModeCount sums a statement's Reached count across inputs.
+	MergeModeCount MergeMode = "count"
+)
+
+// Merge unions functions across one or more parsed coverage inputs,
+// matching functions by {package, name, file} and statements by
+// {start, end} offset, combining Reached per mode. This covers the
+// common case of combining profiles from separate test runs (e.g.
+// `go test ./... -coverpkg=./...` split across shards) into a single
+// report, without needing gocovmerge as an external dependency.
+func Merge(mode MergeMode, packages ...[]*gocov.Package) []*gocov.Package {
+	type fnKey struct {
+		pkg, name, file string
+	}
+	type stmtKey struct {
+		start, end int
+	}
+
+	var pkgOrder []string
+	byPackage := make(map[string]*gocov.Package)
+	byFunction := make(map[fnKey]*gocov.Function)
+	byStatement := make(map[fnKey]map[stmtKey]*gocov.Statement)
+
+	for _, pkgs := range packages {
+		for _, pkg := range pkgs {
+			mp, ok := byPackage[pkg.Name]
+			if !ok {
+				mp = &gocov.Package{Name: pkg.Name}
+				byPackage[pkg.Name] = mp
+				pkgOrder = append(pkgOrder, pkg.Name)
+			}
+			for _, fn := range pkg.Functions {
+				fk := fnKey{pkg.Name, fn.Name, fn.File}
+				mf, ok := byFunction[fk]
+				if !ok {
+					mf = &gocov.Function{Name: fn.Name, File: fn.File, Start: fn.Start, End: fn.End}
+					byFunction[fk] = mf
+					byStatement[fk] = make(map[stmtKey]*gocov.Statement)
+					mp.Functions = append(mp.Functions, mf)
+				}
+				for _, stmt := range fn.Statements {
+					sk := stmtKey{stmt.Start, stmt.End}
+					ms, ok := byStatement[fk][sk]
+					if !ok {
+						ms = &gocov.Statement{Start: stmt.Start, End: stmt.End}
+						byStatement[fk][sk] = ms
+						mf.Statements = append(mf.Statements, ms)
+					}
+					if mode == MergeModeSet {
+						if stmt.Reached > 0 {
+							ms.Reached = 1
+						}
+					} else {
+						ms.Reached += stmt.Reached
+					}
+				}
+			}
+		}
+	}
+
+	merged := make([]*gocov.Package, len(pkgOrder))
+	for i, name := range pkgOrder {
+		merged[i] = byPackage[name]
+	}
+	return merged
+}
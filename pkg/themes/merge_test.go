@@ -0,0 +1,83 @@
+package themes
+
+import (
+	"testing"
+
+	"github.com/axw/gocov"
+)
+
+func pkgFixture(reached1, reached2 int64) []*gocov.Package {
+	return []*gocov.Package{
+		{
+			Name: "example.com/pkg",
+			Functions: []*gocov.Function{
+				{
+					Name:  "Foo",
+					File:  "example.com/pkg/foo.go",
+					Start: 10,
+					End:   40,
+					Statements: []*gocov.Statement{
+						{Start: 15, End: 20, Reached: reached1},
+						{Start: 25, End: 30, Reached: reached2},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestMergeCount(t *testing.T) {
+	a := pkgFixture(1, 0)
+	b := pkgFixture(2, 1)
+
+	merged := Merge(MergeModeCount, a, b)
+	if len(merged) != 1 {
+		t.Fatalf("got %d packages, want 1", len(merged))
+	}
+	fns := merged[0].Functions
+	if len(fns) != 1 {
+		t.Fatalf("got %d functions, want 1", len(fns))
+	}
+	stmts := fns[0].Statements
+	if len(stmts) != 2 {
+		t.Fatalf("got %d statements, want 2", len(stmts))
+	}
+	byStart := map[int]int64{}
+	for _, s := range stmts {
+		byStart[s.Start] = s.Reached
+	}
+	if got := byStart[15]; got != 3 {
+		t.Errorf("statement at 15: Reached = %d, want 3 (1+2)", got)
+	}
+	if got := byStart[25]; got != 1 {
+		t.Errorf("statement at 25: Reached = %d, want 1 (0+1)", got)
+	}
+}
+
+func TestMergeSet(t *testing.T) {
+	a := pkgFixture(0, 1)
+	b := pkgFixture(5, 0)
+
+	merged := Merge(MergeModeSet, a, b)
+	byStart := map[int]int64{}
+	for _, s := range merged[0].Functions[0].Statements {
+		byStart[s.Start] = s.Reached
+	}
+	if got := byStart[15]; got != 1 {
+		t.Errorf("statement at 15: Reached = %d, want 1 (hit in at least one input)", got)
+	}
+	if got := byStart[25]; got != 1 {
+		t.Errorf("statement at 25: Reached = %d, want 1 (hit in at least one input)", got)
+	}
+}
+
+func TestMergeDoesNotDuplicateFunctionsAcrossInputs(t *testing.T) {
+	a := pkgFixture(1, 1)
+	b := pkgFixture(1, 1)
+
+	merged := Merge(MergeModeCount, a, b)
+	if len(merged) != 1 || len(merged[0].Functions) != 1 {
+		t.Fatalf("expected Merge to union by {package, function, file}, got packages=%d functions=%d",
+			len(merged), len(merged[0].Functions))
+	}
+}
@@ -0,0 +1,261 @@
+package themes
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/axw/gocov"
+	"github.com/rotisserie/eris"
+)
+
+// profileBlock is one parsed line of a Go coverage profile, as emitted
+// by `go test -coverprofile=c.out`.
+type profileBlock struct {
+	StartLine, StartCol int
+	EndLine, EndCol     int
+	NumStmt, Count      int
+}
+
+var profileLineRe = regexp.MustCompile(`^(.+):(\d+)\.(\d+),(\d+)\.(\d+) (\d+) (\d+)$`)
+
+// isProfileData reports whether data is a native Go coverage profile,
+// as opposed to gocov JSON: profiles start with a "mode: ..." header.
+func isProfileData(data []byte) bool {
+	line, _, _ := bufio.NewReader(bytes.NewReader(data)).ReadLine()
+	return strings.HasPrefix(strings.TrimSpace(string(line)), "mode:")
+}
+
+// parseProfile parses a Go coverage profile and synthesizes the
+// equivalent gocov package tree, so that the rest of the report
+// pipeline can treat it exactly like data produced by axw/gocov.
+// Source files are located via go/build, then reparsed so that
+// profile blocks can be attributed to the enclosing top-level
+// function declaration.
+func parseProfile(data []byte) (packages []*gocov.Package, mode string, err error) {
+	blocksByFile := make(map[string][]profileBlock)
+	var fileOrder []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "mode:") {
+			mode = strings.TrimSpace(strings.TrimPrefix(line, "mode:"))
+			continue
+		}
+		fileName, block, perr := parseProfileLine(line)
+		if perr != nil {
+			return nil, "", eris.Wrap(perr, "parse coverage profile")
+		}
+		if _, ok := blocksByFile[fileName]; !ok {
+			fileOrder = append(fileOrder, fileName)
+		}
+		blocksByFile[fileName] = append(blocksByFile[fileName], block)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, "", eris.Wrap(err, "read coverage profile")
+	}
+	sort.Strings(fileOrder)
+
+	byPackage := make(map[string]*gocov.Package)
+	var pkgOrder []string
+	for _, fileName := range fileOrder {
+		pkgName, path, perr := resolveProfileFile(fileName)
+		if perr != nil {
+			return nil, "", eris.Wrap(perr, "resolve "+fileName)
+		}
+		fns, perr := functionsForFile(path, blocksByFile[fileName], mode)
+		if perr != nil {
+			return nil, "", eris.Wrap(perr, "parse "+path)
+		}
+		pkg, ok := byPackage[pkgName]
+		if !ok {
+			pkg = &gocov.Package{Name: pkgName}
+			byPackage[pkgName] = pkg
+			pkgOrder = append(pkgOrder, pkgName)
+		}
+		pkg.Functions = append(pkg.Functions, fns...)
+	}
+
+	for _, name := range pkgOrder {
+		packages = append(packages, byPackage[name])
+	}
+	return packages, mode, nil
+}
+
+func parseProfileLine(line string) (fileName string, block profileBlock, err error) {
+	m := profileLineRe.FindStringSubmatch(line)
+	if m == nil {
+		return "", block, fmt.Errorf("invalid coverage profile line: %q", line)
+	}
+	fileName = m[1]
+	block.StartLine, _ = strconv.Atoi(m[2])
+	block.StartCol, _ = strconv.Atoi(m[3])
+	block.EndLine, _ = strconv.Atoi(m[4])
+	block.EndCol, _ = strconv.Atoi(m[5])
+	block.NumStmt, _ = strconv.Atoi(m[6])
+	block.Count, _ = strconv.Atoi(m[7])
+	return fileName, block, nil
+}
+
+// resolveProfileFile turns a profile file name of the form
+// "import/path/file.go" into an import path (used as the gocov
+// package name) and an on-disk path. Coverage profiles always use "/"
+// as the separator regardless of OS (see cmd/cover), so the split
+// must not use filepath.Split, which would use the OS separator.
+//
+// build.Import resolves dir against the process's current working
+// directory (srcDir "."), not anything derived from fileName, so
+// gocov-html must be run from inside the module the profile was
+// captured from — e.g. a CI step that does `cd` before invoking it
+// will fail to resolve otherwise-valid profile paths.
+func resolveProfileFile(fileName string) (pkgName, path string, err error) {
+	dir, base := ".", fileName
+	if i := strings.LastIndex(fileName, "/"); i >= 0 {
+		dir, base = fileName[:i], fileName[i+1:]
+	}
+	pkg, err := build.Import(dir, ".", build.FindOnly)
+	if err != nil {
+		return "", "", err
+	}
+	return dir, filepath.Join(pkg.Dir, base), nil
+}
+
+// functionsForFile parses path and emits one gocov.Function per
+// extent found by funcExtents (top-level func declarations and func
+// literals assigned to package-level vars), with one gocov.Statement
+// per profile block that starts inside it.
+func functionsForFile(path string, blocks []profileBlock, mode string) ([]*gocov.Function, error) {
+	fset := token.NewFileSet()
+	astFile, err := parser.ParseFile(fset, path, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	tokFile := fset.File(astFile.Pos())
+
+	blockOffset := func(line, col int) int {
+		return tokFile.Offset(tokFile.LineStart(line) + token.Pos(col-1))
+	}
+
+	extents := funcExtents(astFile)
+
+	var functions []*gocov.Function
+	for _, ext := range extents {
+		start := tokFile.Offset(ext.node.Pos())
+		end := tokFile.Offset(ext.node.End())
+
+		gf := &gocov.Function{
+			Name:  ext.name,
+			File:  path,
+			Start: start,
+			End:   end,
+		}
+		for _, b := range blocks {
+			bStart := blockOffset(b.StartLine, b.StartCol)
+			bEnd := blockOffset(b.EndLine, b.EndCol)
+			if bStart < start || bStart >= end {
+				continue
+			}
+			reached := b.Count
+			if mode == "set" && reached > 1 {
+				reached = 1
+			}
+			gf.Statements = append(gf.Statements, &gocov.Statement{
+				Start:   bStart,
+				End:     bEnd,
+				Reached: int64(reached),
+			})
+		}
+		functions = append(functions, gf)
+	}
+	return functions, nil
+}
+
+// funcExtent is a named, position-bounded function body that profile
+// blocks can be attributed to: either a top-level func declaration or
+// a func literal assigned to a package-level var, e.g. the common
+// `var handler = func(...) {...}` pattern for handler tables,
+// middleware and strategy vars. Without the latter, profile blocks
+// inside such a literal fall outside every FuncDecl's range and are
+// silently dropped from both the numerator and denominator of every
+// coverage percentage, diverging from what `go test` itself reports.
+type funcExtent struct {
+	name string
+	node ast.Node
+}
+
+// funcExtents collects the named function bodies of f: one per
+// top-level FuncDecl, plus one per func literal assigned to a
+// package-level var (named after that var, mirroring how go tool
+// cover's own anonymous-function handling keys off the enclosing
+// declaration rather than dropping the data).
+func funcExtents(f *ast.File) []funcExtent {
+	var extents []funcExtent
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Body != nil {
+				extents = append(extents, funcExtent{name: funcDeclName(d), node: d})
+			}
+		case *ast.GenDecl:
+			if d.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range d.Specs {
+				vs, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+				for i, value := range vs.Values {
+					lit, ok := value.(*ast.FuncLit)
+					if !ok || i >= len(vs.Names) {
+						continue
+					}
+					extents = append(extents, funcExtent{name: vs.Names[i].Name, node: lit})
+				}
+			}
+		}
+	}
+	return extents
+}
+
+// funcDeclName mirrors the "(*Type).Method" naming go tool cover uses
+// for methods, and the bare function name otherwise.
+func funcDeclName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	return fmt.Sprintf("(%s).%s", types.ExprString(fn.Recv.List[0].Type), fn.Name.Name)
+}
+
+// parseCoverageData parses data as a native Go coverage profile if it
+// looks like one, falling back to gocov JSON otherwise. The returned
+// mode is "" for JSON input, since gocov JSON carries no notion of
+// set/count/atomic coverage.
+func parseCoverageData(data []byte) (packages []*gocov.Package, mode string, err error) {
+	if isProfileData(data) {
+		return parseProfile(data)
+	}
+	packages, err = unmarshalJSON(data)
+	return packages, "", err
+}
+
+// ParseCoverageData is the exported form of parseCoverageData, for
+// callers (such as main's multi-input merge) that need the parsed
+// package tree ahead of handing it to one of the Report functions.
+func ParseCoverageData(data []byte) (packages []*gocov.Package, mode string, err error) {
+	return parseCoverageData(data)
+}
@@ -0,0 +1,147 @@
+package themes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseProfileLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		wantErr bool
+		want    profileBlock
+		file    string
+	}{
+		{
+			name: "well formed count line",
+			line: "example.com/pkg/file.go:3.24,5.2 1 7",
+			file: "example.com/pkg/file.go",
+			want: profileBlock{StartLine: 3, StartCol: 24, EndLine: 5, EndCol: 2, NumStmt: 1, Count: 7},
+		},
+		{
+			name:    "missing count",
+			line:    "example.com/pkg/file.go:3.24,5.2 1",
+			wantErr: true,
+		},
+		{
+			name:    "garbage",
+			line:    "not a profile line",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, block, err := parseProfileLine(tt.line)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if file != tt.file {
+				t.Errorf("file = %q, want %q", file, tt.file)
+			}
+			if block != tt.want {
+				t.Errorf("block = %+v, want %+v", block, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsProfileData(t *testing.T) {
+	if !isProfileData([]byte("mode: count\nexample.com/pkg/file.go:3.24,5.2 1 7\n")) {
+		t.Error("expected a profile with a mode header to be detected as profile data")
+	}
+	if isProfileData([]byte(`{"Packages":[]}`)) {
+		t.Error("expected gocov JSON not to be detected as profile data")
+	}
+}
+
+func TestFunctionsForFile(t *testing.T) {
+	const src = `package sample
+
+func Add(a, b int) int {
+	return a + b
+}
+
+func Sub(a, b int) int {
+	return a - b
+}
+`
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sample.go")
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	blocks := []profileBlock{
+		{StartLine: 4, StartCol: 2, EndLine: 4, EndCol: 15, NumStmt: 1, Count: 3},
+		{StartLine: 8, StartCol: 2, EndLine: 8, EndCol: 15, NumStmt: 1, Count: 0},
+	}
+
+	fns, err := functionsForFile(path, blocks, "count")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fns) != 2 {
+		t.Fatalf("got %d functions, want 2", len(fns))
+	}
+
+	byName := map[string]int64{}
+	for _, fn := range fns {
+		if len(fn.Statements) != 1 {
+			t.Fatalf("function %s: got %d statements, want 1", fn.Name, len(fn.Statements))
+		}
+		byName[fn.Name] = fn.Statements[0].Reached
+	}
+	if got := byName["Add"]; got != 3 {
+		t.Errorf("Add Reached = %d, want 3", got)
+	}
+	if got := byName["Sub"]; got != 0 {
+		t.Errorf("Sub Reached = %d, want 0", got)
+	}
+}
+
+// TestFunctionsForFileVarFuncLit covers the handler-table/middleware
+// pattern where a func literal is assigned directly to a package
+// level var, rather than declared with func; its profile blocks fall
+// outside every *ast.FuncDecl's range and must not be dropped.
+func TestFunctionsForFileVarFuncLit(t *testing.T) {
+	const src = `package sample
+
+var adder = func(a, b int) int {
+	return a + b
+}
+`
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sample.go")
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	blocks := []profileBlock{
+		{StartLine: 4, StartCol: 2, EndLine: 4, EndCol: 15, NumStmt: 1, Count: 5},
+	}
+
+	fns, err := functionsForFile(path, blocks, "count")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fns) != 1 {
+		t.Fatalf("got %d functions, want 1", len(fns))
+	}
+	if fns[0].Name != "adder" {
+		t.Errorf("function name = %q, want %q", fns[0].Name, "adder")
+	}
+	if len(fns[0].Statements) != 1 {
+		t.Fatalf("got %d statements, want 1", len(fns[0].Statements))
+	}
+	if got := fns[0].Statements[0].Reached; got != 5 {
+		t.Errorf("adder Reached = %d, want 5", got)
+	}
+}
@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"go/token"
 	"html"
+	"html/template"
 	"io"
 	"io/ioutil"
+	"math"
 	"os"
 	"path/filepath"
 	"sort"
@@ -27,6 +29,16 @@ type ReportOptions struct {
 	CoverageFunctionMax uint8
 	CoveragePackageMin  uint8
 	CoveragePackageMax  uint8
+
+	// CoverageMode is the coverage mode ("set", "count" or "atomic").
+	// A native Go coverage profile's "mode: ..." header overrides it;
+	// gocov JSON input carries no mode, so it keeps the value the
+	// caller set (empty unless set, e.g. by main when merging inputs).
+	CoverageMode string
+
+	// Verbose enables the per-package reached/total statement counts
+	// on stderr. Off by default so stdout/stderr stay script-friendly.
+	Verbose bool
 }
 
 type report struct {
@@ -88,6 +100,14 @@ func buildReportPackage(pkg *gocov.Package, r *report) reportPackage {
 		Pkg:       pkg,
 		Functions: make(reportFunctionList, 0),
 	}
+	fileMaxCount := make(map[string]int64)
+	for _, fn := range pkg.Functions {
+		for _, stmt := range fn.Statements {
+			if stmt.Reached > fileMaxCount[fn.File] {
+				fileMaxCount[fn.File] = stmt.Reached
+			}
+		}
+	}
 	for _, fn := range pkg.Functions {
 		reached := 0
 		for _, stmt := range fn.Statements {
@@ -95,7 +115,12 @@ func buildReportPackage(pkg *gocov.Package, r *report) reportPackage {
 				reached++
 			}
 		}
-		rf := reportFunction{Function: fn, StatementsReached: reached}
+		rf := reportFunction{
+			Function:          fn,
+			StatementsReached: reached,
+			Mode:              r.CoverageMode,
+			FileMaxCount:      fileMaxCount[fn.File],
+		}
 		covp := rf.CoveragePercent()
 		if covp >= float64(r.CoverageFunctionMin) && covp <= float64(r.CoverageFunctionMax) {
 			rv.Functions = append(rv.Functions, rf)
@@ -119,13 +144,13 @@ func printReport(w io.Writer, r *report) error {
 	data := curTheme.Data()
 
 	// Base64 decoding of style data and script.
-	s, err := base64.StdEncoding.DecodeString(data.Style)
+	s, err := base64.StdEncoding.DecodeString(string(data.Style))
 	if err != nil {
 		return eris.Wrap(err, "decode style")
 	}
 	css := string(s)
 	// Decode the script also.
-	sc, err := base64.StdEncoding.DecodeString(data.Script)
+	sc, err := base64.StdEncoding.DecodeString(string(data.Script))
 	if err != nil {
 		return eris.Wrap(err, "decode script")
 	}
@@ -149,8 +174,8 @@ func printReport(w io.Writer, r *report) error {
 		pkgNames[i] = pkg.Name
 	}
 
-	data.Script = string(sc)
-	data.Style = css
+	data.Script = template.JS(sc)
+	data.Style = template.CSS(css)
 	data.Packages = reportPackages
 	data.Command = fmt.Sprintf("gocov test %s | gocov-html %s",
 		strings.Join(pkgNames, " "),
@@ -178,11 +203,49 @@ func exists(path string) (bool, error) {
 	return true, nil
 }
 
-// HTMLReportCoverage outputs an HTML report on stdout by
-// parsing JSON data generated by axw/gocov. The css parameter
-// is an absolute path to a custom stylesheet. Use an empty
-// string to use the default stylesheet available.
-func HTMLReportCoverage(r io.Reader, opts ReportOptions) error {
+// loadReport parses coverage data (gocov JSON or a native Go coverage
+// profile) and assembles a report, applying the package coverage
+// filters from opts. It's the common entry point shared by the
+// Cobertura and func report formats, which don't need HTMLReportCoverage's
+// custom-stylesheet handling or stderr progress output.
+func loadReport(data []byte, opts ReportOptions) (*report, error) {
+	packages, mode, err := parseCoverageData(data)
+	if err != nil {
+		return nil, eris.Wrap(err, "unmarshal coverage data")
+	}
+
+	rep := newReport()
+	rep.ReportOptions = opts
+	if mode != "" {
+		rep.CoverageMode = mode
+	}
+
+	for _, pkg := range packages {
+		reachedStatements := 0
+		totalStatements := 0
+		for _, fn := range pkg.Functions {
+			for _, stmt := range fn.Statements {
+				totalStatements++
+				if stmt.Reached > 0 {
+					reachedStatements++
+				}
+			}
+		}
+		stmtPercent := float64(reachedStatements) / float64(totalStatements) * 100
+		if stmtPercent >= float64(opts.CoveragePackageMin) && stmtPercent <= float64(opts.CoveragePackageMax) {
+			rep.addPackage(pkg)
+		}
+	}
+	return rep, nil
+}
+
+// HTMLReportCoverage writes an HTML report to w, accepting either
+// JSON data generated by axw/gocov or a native Go coverage profile as
+// produced by `go test -coverprofile`; the input format is
+// auto-detected. opts.Stylesheet is the path to a custom stylesheet,
+// which must exist; an empty string uses the theme's default
+// stylesheet.
+func HTMLReportCoverage(r io.Reader, w io.Writer, opts ReportOptions) error {
 	t0 := time.Now()
 	report := newReport()
 	report.ReportOptions = opts
@@ -202,10 +265,13 @@ func HTMLReportCoverage(r io.Reader, opts ReportOptions) error {
 		return eris.Wrap(err, "read coverage data")
 	}
 
-	packages, err := unmarshalJSON(data)
+	packages, mode, err := parseCoverageData(data)
 	if err != nil {
 		return eris.Wrap(err, "unmarshal coverage data")
 	}
+	if mode != "" {
+		report.CoverageMode = mode
+	}
 
 	for _, pkg := range packages {
 		reachedStatements := 0
@@ -223,14 +289,17 @@ func HTMLReportCoverage(r io.Reader, opts ReportOptions) error {
 
 		stmtPercent := float64(reachedStatements) / float64(totalStatements) * 100
 
-		fmt.Fprintf(os.Stderr, fmt.Sprintf("[%s] - reachedStatements: %v - totalStatements: %v - stmtPercent: %v\n", pkg.Name, reachedStatements, totalStatements, stmtPercent))
+		if opts.Verbose {
+			fmt.Fprintf(os.Stderr, "[%s] - reachedStatements: %v - totalStatements: %v - stmtPercent: %v\n", pkg.Name, reachedStatements, totalStatements, stmtPercent)
+		}
 		if stmtPercent >= float64(opts.CoveragePackageMin) && stmtPercent <= float64(opts.CoveragePackageMax) {
 			report.addPackage(pkg)
 		}
 	}
-	fmt.Println()
-	err = printReport(os.Stdout, report)
-	fmt.Fprintf(os.Stderr, "Took %v\n", time.Since(t0))
+	err = printReport(w, report)
+	if opts.Verbose {
+		fmt.Fprintf(os.Stderr, "Took %v\n", time.Since(t0))
+	}
 	return eris.Wrap(err, "HTML report")
 }
 
@@ -262,6 +331,15 @@ func (rp *reportPackage) PercentageReached() float64 {
 type reportFunction struct {
 	*gocov.Function
 	StatementsReached int
+	// Mode is the coverage mode ("set", "count" or "atomic") the
+	// function's statement counts were produced under, taken from
+	// ReportOptions.CoverageMode, or "" when no mode is known (gocov
+	// JSON input with no mode set). Only "count" and "atomic" carry meaningful
+	// hit counts, so only those get a heat-map rendering.
+	Mode string
+	// FileMaxCount is the highest statement hit count anywhere in the
+	// function's source file, used to normalize Lines' HeatBucket.
+	FileMaxCount int64
 }
 
 // functionLine holds the line of code, its line number in the source file
@@ -270,6 +348,31 @@ type functionLine struct {
 	Code       string
 	LineNumber int
 	Missed     bool
+	// Count is the highest statement hit count on this line. Only
+	// populated in "count"/"atomic" mode.
+	Count int64
+	// HeatBucket buckets Count into 0..heatBuckets shades on a log
+	// scale relative to the function's FileMaxCount, matching the
+	// heat-map go tool cover -html produces.
+	HeatBucket int
+}
+
+// heatBuckets is the number of distinct heat-map shades (cov0..covN)
+// built-in themes are expected to provide CSS for.
+const heatBuckets = 10
+
+// heatBucket buckets count into 0..heatBuckets on a log scale relative
+// to max, so that a handful of very hot lines don't wash out the rest
+// of the gradient.
+func heatBucket(count, max int64) int {
+	if max <= 0 || count <= 0 {
+		return 0
+	}
+	bucket := int(math.Log(float64(count)+1) / math.Log(float64(max)+1) * heatBuckets)
+	if bucket > heatBuckets {
+		bucket = heatBuckets
+	}
+	return bucket
 }
 
 // CoveragePercent is the percentage of code coverage for a function. Returns 100
@@ -333,6 +436,7 @@ func (f reportFunction) Lines() []functionLine {
 		lineno := lineno + i
 		statementFound := false
 		hit := false
+		var count int64
 		for j := 0; j < len(statements); j++ {
 			start := file.Line(file.Pos(statements[j].Start))
 			if start == lineno {
@@ -340,6 +444,9 @@ func (f reportFunction) Lines() []functionLine {
 				if !hit && statements[j].Reached > 0 {
 					hit = true
 				}
+				if statements[j].Reached > count {
+					count = statements[j].Reached
+				}
 				statements = append(statements[:j], statements[j+1:]...)
 			}
 		}
@@ -347,11 +454,16 @@ func (f reportFunction) Lines() []functionLine {
 		if statementFound && !hit {
 			hitmiss = missPrefix
 		}
-		fls[i] = functionLine{
+		fl := functionLine{
 			Missed:     hitmiss == missPrefix,
 			LineNumber: lineno,
 			Code:       html.EscapeString(strings.Replace(line, "\t", "    ", -1)),
 		}
+		if f.Mode == "count" || f.Mode == "atomic" {
+			fl.Count = count
+			fl.HeatBucket = heatBucket(count, f.FileMaxCount)
+		}
+		fls[i] = fl
 	}
 	return fls
 }
@@ -0,0 +1,62 @@
+package themes
+
+import (
+	"fmt"
+	"html/template"
+)
+
+// Theme renders a report into HTML. Built-in themes register
+// themselves from an init function via registerTheme.
+type Theme interface {
+	Name() string
+	Description() string
+	Data() ThemeData
+	Template() *template.Template
+}
+
+// ThemeData is handed to a theme's template by printReport. Style and
+// Script start out base64-encoded, so built-in themes can embed them
+// as plain Go string constants, and are swapped for their decoded
+// form before the template executes. They use html/template's safe
+// types rather than string, so the CSS/JS auto-escaper renders them
+// verbatim instead of replacing them with "ZgotmplZ".
+type ThemeData struct {
+	Style    template.CSS
+	Script   template.JS
+	Packages reportPackageList
+	Command  string
+	Overview *reportPackage
+}
+
+var (
+	registry = map[string]Theme{}
+	curTheme Theme
+)
+
+func registerTheme(t Theme) {
+	registry[t.Name()] = t
+}
+
+// List returns all registered themes, for the "-lt" flag.
+func List() []Theme {
+	all := make([]Theme, 0, len(registry))
+	for _, t := range registry {
+		all = append(all, t)
+	}
+	return all
+}
+
+// Use selects the theme HTMLReportCoverage renders with.
+func Use(name string) error {
+	t, ok := registry[name]
+	if !ok {
+		return fmt.Errorf("no such theme: %q", name)
+	}
+	curTheme = t
+	return nil
+}
+
+// Current returns the theme selected by the most recent call to Use.
+func Current() Theme {
+	return curTheme
+}